@@ -3,22 +3,35 @@ package main
 import (
 	"bufio"
 	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/hex"
 	"encoding/json"
+	"encoding/xml"
+	"errors"
 	"fmt"
 	"io"
+	"mime/multipart"
 	"net/http"
 	"os"
+	"os/exec"
 	"path/filepath"
+	"sort"
 	"strconv"
 	"strings"
 	"sync"
 	"time"
+	"unicode"
 )
 
 // OpenAI API endpoints and models configuration
 const (
-	OpenAITTSEndpoint = "https://api.openai.com/v1/audio/speech"
-	OpenAIModelTTS    = "tts-1-hd" // changed to "tts-1-hd"
+	OpenAITTSEndpoint           = "https://api.openai.com/v1/audio/speech"
+	OpenAIModelTTS              = "tts-1-hd" // changed to "tts-1-hd"
+	OpenAITranscriptionEndpoint = "https://api.openai.com/v1/audio/transcriptions"
+	OpenAIModelTranscription    = "whisper-1"
 )
 
 // Available options for podcast generation
@@ -34,11 +47,12 @@ var (
 		"Slovenian", "Spanish", "Swahili", "Swedish", "Tagalog", "Tamil", "Thai",
 		"Turkish", "Ukrainian", "Urdu", "Vietnamese", "Welsh",
 	}
-	availableVoices = []string{
-		"alloy", "ash", "coral", "echo", "fable", "onyx", "nova", "sage", "shimmer",
-	}
 )
 
+// defaultTTSBackend is selected when --backend is not given, preserving the
+// tool's original OpenAI-only behavior.
+const defaultTTSBackend = "openai"
+
 // PromptData holds all the user input for the podcast
 type PromptData struct {
 	TextFile string
@@ -73,6 +87,48 @@ func (e *PodcastError) Error() string {
 	return fmt.Sprintf("Error during %s: %s", e.Stage, e.Message)
 }
 
+// retryableError marks a backend failure as transient (HTTP 429 or 5xx),
+// carrying any Retry-After the server sent so the caller's retry loop can
+// honor it instead of guessing a backoff.
+type retryableError struct {
+	RetryAfter time.Duration
+	Err        error
+}
+
+func (e *retryableError) Error() string { return e.Err.Error() }
+func (e *retryableError) Unwrap() error { return e.Err }
+
+// parseRetryAfter reads a Retry-After header expressed as a number of
+// seconds (the HTTP-date form is not supported). It returns 0 if the header
+// is absent or unparsable, meaning "fall back to the caller's own backoff."
+func parseRetryAfter(resp *http.Response) time.Duration {
+	v := resp.Header.Get("Retry-After")
+	if v == "" {
+		return 0
+	}
+	secs, err := strconv.Atoi(v)
+	if err != nil || secs < 0 {
+		return 0
+	}
+	return time.Duration(secs) * time.Second
+}
+
+// httpStatusError builds the error a TTS backend should return for a
+// non-200 response. Status 429 and 5xx responses are wrapped as
+// retryableError so generateTTSAudio's retry loop can back off and try
+// again instead of failing the whole chunk immediately.
+func httpStatusError(resp *http.Response, message string, body []byte) error {
+	err := &PodcastError{
+		Stage:   "audio generation",
+		Message: message,
+		Err:     fmt.Errorf(string(body)),
+	}
+	if resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500 {
+		return &retryableError{RetryAfter: parseRetryAfter(resp), Err: err}
+	}
+	return err
+}
+
 // checkAPIKey verifies the presence and validity of the OpenAI API key
 func checkAPIKey() error {
 	apiKey := os.Getenv("OPENAI_API_KEY")
@@ -115,315 +171,1728 @@ func checkAPIKey() error {
 	return nil
 }
 
-func main() {
-	fmt.Println("=== Text-to-Speech Generator ===")
+// TTSBackend abstracts over text-to-speech providers so the generation
+// pipeline can target OpenAI, Azure OpenAI, LocalAI, Google Cloud, or a
+// local Piper binary without changing how chunks are split or assembled.
+type TTSBackend interface {
+	// Synthesize renders text as audio bytes using the given voice, with lang
+	// available as a hint for backends that need it (e.g. Google Cloud).
+	Synthesize(ctx context.Context, text, voice, lang string) ([]byte, error)
+	// Voices lists the voice names this backend supports.
+	Voices() []string
+	// Name identifies the backend for cache keys and logs (e.g. "openai").
+	Name() string
+	// Model identifies the underlying model/deployment used for synthesis,
+	// so the chunk cache can tell apart audio produced with different ones.
+	Model() string
+	// Format identifies the audio container Synthesize returns ("mp3" or
+	// "wav"), so the pipeline knows whether to assemble chunks with the
+	// frame-aware MP3 merger or the WAV one.
+	Format() string
+}
 
-	// Initial API key verification
-	fmt.Println("Checking OpenAI API key...")
-	if err := checkAPIKey(); err != nil {
-		fmt.Printf("\n❌ %v\n", err)
-		os.Exit(1)
+// newTTSBackend builds the backend selected by --backend. An empty name
+// selects OpenAI, matching the tool's original behavior.
+func newTTSBackend(name string) (TTSBackend, error) {
+	switch name {
+	case "", defaultTTSBackend:
+		return newOpenAIBackend()
+	case "azure":
+		return newAzureOpenAIBackend()
+	case "localai":
+		return newLocalAIBackend()
+	case "google":
+		return newGoogleCloudBackend()
+	case "piper":
+		return newPiperBackend()
+	default:
+		return nil, &PodcastError{
+			Stage:   "backend init",
+			Message: fmt.Sprintf("unknown TTS backend %q (want one of: openai, azure, localai, google, piper)", name),
+		}
 	}
-	fmt.Println("✅ API key valid")
+}
 
-	// Vérifier si un fichier a été passé en argument
-	var promptData PromptData
-	if len(os.Args) > 1 {
-		textFile := os.Args[1]
-		// Vérifier si le fichier existe
-		if _, err := os.Stat(textFile); os.IsNotExist(err) {
-			fmt.Printf("❌ File not found: %s\n", textFile)
-			os.Exit(1)
+// openAIBackend is the original backend: OpenAI's hosted TTS endpoint.
+type openAIBackend struct {
+	apiKey string
+}
+
+func newOpenAIBackend() (*openAIBackend, error) {
+	apiKey := os.Getenv("OPENAI_API_KEY")
+	if apiKey == "" {
+		return nil, &PodcastError{
+			Stage:   "backend init",
+			Message: "OPENAI_API_KEY environment variable not set",
 		}
-		// Collecter uniquement les autres paramètres
-		promptData = collectUserInputWithFile(textFile)
-	} else {
-		// Collecter tous les paramètres via l'interface interactive
-		promptData = collectUserInput()
 	}
+	return &openAIBackend{apiKey: apiKey}, nil
+}
 
-	// 2. Recap the information
-	fmt.Println("\nRecap:")
-	fmt.Printf("  Text File : %s\n", promptData.TextFile)
-	fmt.Printf("  Voice    : %s\n", promptData.Voice)
-	fmt.Printf("  Language : %s\n", promptData.Language)
+func (b *openAIBackend) Voices() []string {
+	return []string{"alloy", "ash", "coral", "echo", "fable", "onyx", "nova", "sage", "shimmer"}
+}
 
-	// 3. Load text from file
-	fmt.Println("\nLoading text from file...")
-	content, err := os.ReadFile(promptData.TextFile)
+func (b *openAIBackend) Name() string { return "openai" }
+
+func (b *openAIBackend) Model() string { return OpenAIModelTTS }
+
+func (b *openAIBackend) Format() string { return "mp3" }
+
+func (b *openAIBackend) Synthesize(ctx context.Context, text, voice, lang string) ([]byte, error) {
+	payload := map[string]interface{}{
+		"model": OpenAIModelTTS,
+		"input": text,
+		"voice": voice,
+	}
+	if isSSMLText(text) {
+		payload["input_format"] = "ssml"
+	}
+	jsonData, err := json.Marshal(payload)
 	if err != nil {
-		fmt.Printf("\n❌ Error reading file: %v\n", err)
-		os.Exit(1)
+		return nil, err
 	}
-	generatedText := string(content)
-	fmt.Printf("✅ Text loaded (%d characters)\n", len(generatedText))
 
-	// 4. Split the text into chunks (<= 2800 chars)
-	fmt.Println("\nSplitting text into chunks ...")
-	chunks := splitTextIntoChunks(generatedText, 2800)
-	fmt.Printf("Created %d chunk(s).\n", len(chunks))
+	req, err := http.NewRequestWithContext(ctx, "POST", OpenAITTSEndpoint, bytes.NewBuffer(jsonData))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Authorization", "Bearer "+b.apiKey)
+	req.Header.Set("Content-Type", "application/json")
 
-	// 5. Create a temporary directory to store partial audio files
-	tmpDir, err := os.MkdirTemp(".", "podcast_tmp_")
+	client := &http.Client{}
+	resp, err := client.Do(req)
 	if err != nil {
-		fmt.Printf("Error creating temp directory: %v\n", err)
-		os.Exit(1)
+		return nil, err
 	}
-	defer os.RemoveAll(tmpDir)
+	defer resp.Body.Close()
 
-	// 6. Générer l'audio pour tous les chunks en parallèle
-	fmt.Println("\nGenerating audio for all chunks in parallel...")
-	audioFiles, err := generateTTSAudioParallel(chunks, promptData.Voice, tmpDir)
+	body, err := io.ReadAll(resp.Body)
 	if err != nil {
-		fmt.Printf("\n❌ Error during audio generation: %v\n", err)
-		os.Exit(1)
+		return nil, err
 	}
-	fmt.Println("\n✅ Audio generation complete.")
 
-	// 7. Concatenate all partial MP3 files
-	finalOutput := "podcast_final.mp3"
-	fmt.Printf("\nAssembling chunks into final file: %s\n", finalOutput)
-	err = concatenateMP3Files(audioFiles, finalOutput)
+	if resp.StatusCode != http.StatusOK {
+		fmt.Printf("\n🔍 OpenAI API Response:\n%s\n", string(body))
+		return nil, httpStatusError(resp, fmt.Sprintf("TTS request failed (status %d)", resp.StatusCode), body)
+	}
+
+	return body, nil
+}
+
+// azureOpenAIBackend targets an Azure OpenAI TTS deployment, which is
+// addressed by resource endpoint and deployment name rather than a model
+// string, and authenticates with an api-key header instead of a bearer
+// token.
+type azureOpenAIBackend struct {
+	apiKey     string
+	endpoint   string
+	deployment string
+}
+
+func newAzureOpenAIBackend() (*azureOpenAIBackend, error) {
+	apiKey := os.Getenv("AZURE_OPENAI_API_KEY")
+	endpoint := os.Getenv("AZURE_OPENAI_ENDPOINT")
+	deployment := os.Getenv("AZURE_OPENAI_TTS_DEPLOYMENT")
+	if apiKey == "" || endpoint == "" || deployment == "" {
+		return nil, &PodcastError{
+			Stage:   "backend init",
+			Message: "AZURE_OPENAI_API_KEY, AZURE_OPENAI_ENDPOINT and AZURE_OPENAI_TTS_DEPLOYMENT must all be set",
+		}
+	}
+	return &azureOpenAIBackend{
+		apiKey:     apiKey,
+		endpoint:   strings.TrimRight(endpoint, "/"),
+		deployment: deployment,
+	}, nil
+}
+
+func (b *azureOpenAIBackend) Voices() []string {
+	return []string{"alloy", "ash", "coral", "echo", "fable", "onyx", "nova", "sage", "shimmer"}
+}
+
+func (b *azureOpenAIBackend) Name() string { return "azure" }
+
+func (b *azureOpenAIBackend) Model() string { return b.deployment }
+
+func (b *azureOpenAIBackend) Format() string { return "mp3" }
+
+func (b *azureOpenAIBackend) Synthesize(ctx context.Context, text, voice, lang string) ([]byte, error) {
+	url := fmt.Sprintf("%s/openai/deployments/%s/audio/speech?api-version=2024-08-01-preview", b.endpoint, b.deployment)
+
+	payload := map[string]interface{}{
+		"model": b.deployment,
+		"input": text,
+		"voice": voice,
+	}
+	if isSSMLText(text) {
+		payload["input_format"] = "ssml"
+	}
+	jsonData, err := json.Marshal(payload)
 	if err != nil {
-		fmt.Printf("Error assembling final MP3: %v\n", err)
-		os.Exit(1)
+		return nil, err
 	}
 
-	fmt.Println("✅ Audio assembly complete!")
-	fmt.Printf("Your final audio is saved as '%s'\n", finalOutput)
+	req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewBuffer(jsonData))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("api-key", b.apiKey)
+	req.Header.Set("Content-Type", "application/json")
+
+	client := &http.Client{}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, httpStatusError(resp, fmt.Sprintf("Azure TTS request failed (status %d)", resp.StatusCode), body)
+	}
+
+	return body, nil
 }
 
-// getValidChoice displays options and gets a valid choice from user
-func getValidChoice(reader *bufio.Reader, options []string, prompt string) string {
-	for {
-		fmt.Printf("\nAvailable %s:\n", prompt)
-		for i, opt := range options {
-			fmt.Printf("%d. %s\n", i+1, opt)
+// localAIBackend targets a self-hosted LocalAI instance's /tts endpoint,
+// letting the whole pipeline run fully offline.
+type localAIBackend struct {
+	baseURL string
+}
+
+func newLocalAIBackend() (*localAIBackend, error) {
+	baseURL := os.Getenv("LOCALAI_BASE_URL")
+	if baseURL == "" {
+		baseURL = "http://localhost:8080"
+	}
+	return &localAIBackend{baseURL: strings.TrimRight(baseURL, "/")}, nil
+}
+
+func (b *localAIBackend) Voices() []string {
+	return []string{"default"}
+}
+
+func (b *localAIBackend) Name() string { return "localai" }
+
+func (b *localAIBackend) Model() string { return "localai-tts" }
+
+// Format reports wav: LocalAI's /tts endpoint has no response_format
+// parameter like the OpenAI-compatible speech endpoint does, and returns
+// WAV by default.
+func (b *localAIBackend) Format() string { return "wav" }
+
+func (b *localAIBackend) Synthesize(ctx context.Context, text, voice, lang string) ([]byte, error) {
+	payload := map[string]interface{}{
+		"input": text,
+		"model": voice,
+	}
+	jsonData, err := json.Marshal(payload)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", b.baseURL+"/tts", bytes.NewBuffer(jsonData))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	client := &http.Client{}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, httpStatusError(resp, fmt.Sprintf("LocalAI TTS request failed (status %d)", resp.StatusCode), body)
+	}
+
+	return body, nil
+}
+
+// googleLanguageCodes maps a handful of availableLanguages entries to the
+// BCP-47 codes Google Cloud Text-to-Speech expects; anything not listed
+// falls back to US English.
+var googleLanguageCodes = map[string]string{
+	"English":    "en-US",
+	"French":     "fr-FR",
+	"Spanish":    "es-ES",
+	"German":     "de-DE",
+	"Italian":    "it-IT",
+	"Portuguese": "pt-PT",
+	"Japanese":   "ja-JP",
+	"Chinese":    "cmn-CN",
+	"Korean":     "ko-KR",
+	"Russian":    "ru-RU",
+}
+
+func googleLanguageCode(lang string) string {
+	if code, ok := googleLanguageCodes[lang]; ok {
+		return code
+	}
+	return "en-US"
+}
+
+// googleCloudBackend targets the Google Cloud Text-to-Speech REST API,
+// authenticated with a simple API key.
+type googleCloudBackend struct {
+	apiKey string
+}
+
+func newGoogleCloudBackend() (*googleCloudBackend, error) {
+	apiKey := os.Getenv("GOOGLE_TTS_API_KEY")
+	if apiKey == "" {
+		return nil, &PodcastError{
+			Stage:   "backend init",
+			Message: "GOOGLE_TTS_API_KEY environment variable not set",
 		}
-		fmt.Print("Enter your choice (number): ")
-		input, _ := reader.ReadString('\n')
-		input = strings.TrimSpace(input)
+	}
+	return &googleCloudBackend{apiKey: apiKey}, nil
+}
 
-		if idx, err := strconv.Atoi(input); err == nil && idx > 0 && idx <= len(options) {
-			return options[idx-1]
+func (b *googleCloudBackend) Voices() []string {
+	return []string{"en-US-Neural2-A", "en-US-Neural2-C", "en-US-Wavenet-D"}
+}
+
+func (b *googleCloudBackend) Name() string { return "google" }
+
+func (b *googleCloudBackend) Model() string { return "google-cloud-tts" }
+
+func (b *googleCloudBackend) Format() string { return "mp3" }
+
+func (b *googleCloudBackend) Synthesize(ctx context.Context, text, voice, lang string) ([]byte, error) {
+	payload := map[string]interface{}{
+		"input": map[string]string{"text": text},
+		"voice": map[string]string{
+			"languageCode": googleLanguageCode(lang),
+			"name":         voice,
+		},
+		"audioConfig": map[string]string{"audioEncoding": "MP3"},
+	}
+	jsonData, err := json.Marshal(payload)
+	if err != nil {
+		return nil, err
+	}
+
+	url := "https://texttospeech.googleapis.com/v1/text:synthesize?key=" + b.apiKey
+	req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewBuffer(jsonData))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	client := &http.Client{}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, httpStatusError(resp, fmt.Sprintf("Google Cloud TTS request failed (status %d)", resp.StatusCode), respBody)
+	}
+
+	var result struct {
+		AudioContent string `json:"audioContent"`
+	}
+	if err := json.Unmarshal(respBody, &result); err != nil {
+		return nil, &PodcastError{
+			Stage:   "audio generation",
+			Message: "Failed to parse Google Cloud TTS response",
+			Err:     err,
+		}
+	}
+
+	audio, err := base64.StdEncoding.DecodeString(result.AudioContent)
+	if err != nil {
+		return nil, &PodcastError{
+			Stage:   "audio generation",
+			Message: "Failed to decode Google Cloud TTS audio",
+			Err:     err,
 		}
-		fmt.Println("Invalid choice. Please try again.")
 	}
+
+	return audio, nil
 }
 
-// collectUserInput asks the user (via console) for the required inputs
-func collectUserInput() PromptData {
-	reader := bufio.NewReader(os.Stdin)
+// piperBackend drives a local Piper binary for fully offline synthesis.
+// Piper voices are identified by the path to their .onnx model rather than
+// a name, so Voices() just reports the configured model's file name.
+type piperBackend struct {
+	binaryPath string
+	modelPath  string
+}
 
-	fmt.Print("Enter the path to your text file: ")
-	textFile, _ := reader.ReadString('\n')
-	textFile = strings.TrimSpace(textFile)
+func newPiperBackend() (*piperBackend, error) {
+	binaryPath := os.Getenv("PIPER_BINARY")
+	if binaryPath == "" {
+		binaryPath = "piper"
+	}
+	modelPath := os.Getenv("PIPER_MODEL")
+	if modelPath == "" {
+		return nil, &PodcastError{
+			Stage:   "backend init",
+			Message: "PIPER_MODEL environment variable not set (path to a .onnx voice model)",
+		}
+	}
+	return &piperBackend{binaryPath: binaryPath, modelPath: modelPath}, nil
+}
 
-	// Vérifier si le fichier existe
-	if _, err := os.Stat(textFile); os.IsNotExist(err) {
-		fmt.Printf("❌ File not found: %s\n", textFile)
-		os.Exit(1)
+func (b *piperBackend) Voices() []string {
+	return []string{filepath.Base(b.modelPath)}
+}
+
+func (b *piperBackend) Name() string { return "piper" }
+
+func (b *piperBackend) Model() string { return b.modelPath }
+
+// Format reports wav: Piper's CLI writes a self-contained WAV file, not MP3.
+func (b *piperBackend) Format() string { return "wav" }
+
+func (b *piperBackend) Synthesize(ctx context.Context, text, voice, lang string) ([]byte, error) {
+	// --output_file /dev/stdout writes a complete WAV file (header included)
+	// to stdout. --output-raw instead emits headerless raw PCM, which the
+	// rest of the pipeline (caching, concatenation) can't tell apart from a
+	// real audio container.
+	cmd := exec.CommandContext(ctx, b.binaryPath, "--model", b.modelPath, "--output_file", "/dev/stdout")
+	cmd.Stdin = strings.NewReader(text)
+
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		return nil, &PodcastError{
+			Stage:   "audio generation",
+			Message: fmt.Sprintf("Piper synthesis failed: %s", strings.TrimSpace(stderr.String())),
+			Err:     err,
+		}
 	}
 
-	voice := getValidChoice(reader, availableVoices, "voices")
-	language := getValidChoice(reader, availableLanguages, "languages")
+	return stdout.Bytes(), nil
+}
 
-	return PromptData{
-		TextFile: textFile,
-		Voice:    voice,
-		Language: language,
+// extractBackendFlag scans args for a --backend=<name> or --backend <name>
+// flag and returns the selected backend name (empty for the default) along
+// with the remaining arguments.
+// extractStringFlag scans args for a --name=value or --name value flag and
+// returns its value (or def if absent) along with the remaining arguments.
+func extractStringFlag(args []string, name, def string) (string, []string) {
+	value := def
+	var rest []string
+
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		switch {
+		case strings.HasPrefix(arg, "--"+name+"="):
+			value = strings.TrimPrefix(arg, "--"+name+"=")
+		case arg == "--"+name && i+1 < len(args):
+			value = args[i+1]
+			i++
+		default:
+			rest = append(rest, arg)
+		}
 	}
+
+	return value, rest
 }
 
-// Nouvelle fonction pour collecter les entrées avec fichier prédéfini
-func collectUserInputWithFile(textFile string) PromptData {
-	reader := bufio.NewReader(os.Stdin)
-	voice := getValidChoice(reader, availableVoices, "voices")
-	language := getValidChoice(reader, availableLanguages, "languages")
+// extractBoolFlag scans args for a --name boolean flag and reports whether
+// it was present, along with the remaining arguments.
+func extractBoolFlag(args []string, name string) (bool, []string) {
+	var present bool
+	var rest []string
+
+	for _, arg := range args {
+		if arg == "--"+name {
+			present = true
+			continue
+		}
+		rest = append(rest, arg)
+	}
+
+	return present, rest
+}
+
+func extractBackendFlag(args []string) (string, []string) {
+	return extractStringFlag(args, "backend", "")
+}
+
+// cacheDefaultDir returns ~/.cache/autopodcastgpt, the default location for
+// the persistent chunk cache.
+func cacheDefaultDir() string {
+	base, err := os.UserCacheDir()
+	if err != nil {
+		base = "."
+	}
+	return filepath.Join(base, "autopodcastgpt")
+}
+
+// normalizeTextForCache collapses whitespace so cosmetic differences (extra
+// spaces, trailing newlines) don't produce different cache keys for text
+// that would synthesize identically.
+func normalizeTextForCache(text string) string {
+	return strings.Join(strings.Fields(text), " ")
+}
+
+// chunkCachePath returns the path a chunk's audio would be cached at under
+// cacheDir: sha256(backend|model|voice|normalized_text) + the backend's
+// container extension (.mp3 or .wav). Including the voice and model in the
+// key means switching either busts the cache instead of silently serving
+// stale audio.
+func chunkCachePath(cacheDir, backendName, model, voice, text, format string) string {
+	sum := sha256.Sum256([]byte(backendName + "|" + model + "|" + voice + "|" + normalizeTextForCache(text)))
+	return filepath.Join(cacheDir, hex.EncodeToString(sum[:])+"."+format)
+}
+
+// synthesizeCached returns text's audio for voice/lang via backend,
+// consulting the on-disk chunk cache first unless noCache is set. A hit
+// skips the API call entirely; a miss synthesizes and then writes the
+// result back to the cache for next time.
+func synthesizeCached(ctx context.Context, backend TTSBackend, text, voice, lang, cacheDir string, noCache bool) ([]byte, error) {
+	if len(strings.TrimSpace(text)) == 0 {
+		return nil, &PodcastError{
+			Stage:   "audio generation",
+			Message: "Empty text chunk",
+		}
+	}
+
+	var cachePath string
+	if !noCache {
+		cachePath = chunkCachePath(cacheDir, backend.Name(), backend.Model(), voice, text, backend.Format())
+		if data, err := os.ReadFile(cachePath); err == nil {
+			return data, nil
+		}
+	}
+
+	audio, err := backend.Synthesize(ctx, text, voice, lang)
+	if err != nil {
+		return nil, err
+	}
+	if len(audio) == 0 {
+		return nil, &PodcastError{
+			Stage:   "audio generation",
+			Message: "Generated audio file is empty",
+		}
+	}
+
+	if cachePath != "" {
+		if err := os.MkdirAll(cacheDir, 0755); err == nil {
+			_ = os.WriteFile(cachePath, audio, 0644) // best-effort: a cache write failure shouldn't fail the run
+		}
+	}
+
+	return audio, nil
+}
+
+// ttsMaxAttempts bounds how many times withBackendRetry will try a chunk
+// before giving up on it.
+const ttsMaxAttempts = 5
+
+// withBackendRetry calls synthesizeCached, retrying with exponential
+// backoff (starting at 1s, doubling each time) when the failure is a
+// retryableError (HTTP 429/5xx), honoring any Retry-After the server sent
+// in place of the computed backoff. Non-retryable errors (bad request,
+// auth failure, empty chunk, ...) are returned immediately. A single
+// transient 500 no longer fails the whole batch on the first try.
+func withBackendRetry(ctx context.Context, backend TTSBackend, text, voice, lang, cacheDir string, noCache bool) ([]byte, error) {
+	backoff := time.Second
+
+	var lastErr error
+	for attempt := 1; attempt <= ttsMaxAttempts; attempt++ {
+		audio, err := synthesizeCached(ctx, backend, text, voice, lang, cacheDir, noCache)
+		if err == nil {
+			return audio, nil
+		}
+		lastErr = err
+
+		var retryable *retryableError
+		if !errors.As(err, &retryable) || attempt == ttsMaxAttempts {
+			return nil, err
+		}
+
+		wait := backoff
+		if retryable.RetryAfter > 0 {
+			wait = retryable.RetryAfter
+		}
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(wait):
+		}
+		backoff *= 2
+	}
+
+	return nil, lastErr
+}
+
+// cacheGC prunes cacheDir: entries older than maxAge are removed first,
+// then, if maxSize >= 0 and the remaining cache still exceeds it, the
+// oldest remaining entries are removed until it fits.
+func cacheGC(cacheDir string, maxAge time.Duration, maxSize int64) (removed int, freed int64, err error) {
+	entries, err := os.ReadDir(cacheDir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return 0, 0, nil
+		}
+		return 0, 0, err
+	}
+
+	type cacheFile struct {
+		path    string
+		size    int64
+		modTime time.Time
+	}
+	var files []cacheFile
+	for _, e := range entries {
+		if e.IsDir() {
+			continue
+		}
+		info, err := e.Info()
+		if err != nil {
+			continue
+		}
+		files = append(files, cacheFile{
+			path:    filepath.Join(cacheDir, e.Name()),
+			size:    info.Size(),
+			modTime: info.ModTime(),
+		})
+	}
+
+	now := time.Now()
+	var kept []cacheFile
+	for _, f := range files {
+		if maxAge > 0 && now.Sub(f.modTime) > maxAge {
+			if err := os.Remove(f.path); err != nil {
+				return removed, freed, err
+			}
+			removed++
+			freed += f.size
+			continue
+		}
+		kept = append(kept, f)
+	}
+
+	if maxSize >= 0 {
+		sort.Slice(kept, func(i, j int) bool { return kept[i].modTime.Before(kept[j].modTime) })
+
+		var total int64
+		for _, f := range kept {
+			total += f.size
+		}
+		for i := 0; total > maxSize && i < len(kept); i++ {
+			if err := os.Remove(kept[i].path); err != nil {
+				return removed, freed, err
+			}
+			removed++
+			freed += kept[i].size
+			total -= kept[i].size
+		}
+	}
+
+	return removed, freed, nil
+}
+
+// runCache implements the `cache` subcommand family (currently just `gc`,
+// which prunes old or oversized entries from the on-disk chunk cache).
+func runCache(args []string, cacheDir string) {
+	if len(args) == 0 || args[0] != "gc" {
+		fmt.Println("❌ Usage: autopodcastgpt cache gc [--max-age <duration>] [--max-size <bytes>]")
+		os.Exit(1)
+	}
+
+	gcArgs := args[1:]
+	maxAgeStr, gcArgs := extractStringFlag(gcArgs, "max-age", "720h") // 30 days
+	maxSizeStr, _ := extractStringFlag(gcArgs, "max-size", "")
+
+	maxAge, err := time.ParseDuration(maxAgeStr)
+	if err != nil {
+		fmt.Printf("❌ Invalid --max-age %q: %v\n", maxAgeStr, err)
+		os.Exit(1)
+	}
+
+	maxSize := int64(-1)
+	if maxSizeStr != "" {
+		maxSize, err = strconv.ParseInt(maxSizeStr, 10, 64)
+		if err != nil {
+			fmt.Printf("❌ Invalid --max-size %q: %v\n", maxSizeStr, err)
+			os.Exit(1)
+		}
+	}
+
+	removed, freed, err := cacheGC(cacheDir, maxAge, maxSize)
+	if err != nil {
+		fmt.Printf("\n❌ %v\n", err)
+		os.Exit(1)
+	}
+
+	fmt.Printf("✅ Removed %d cached chunk(s), freeing %d bytes\n", removed, freed)
+}
+
+func main() {
+	backendName, args := extractBackendFlag(os.Args[1:])
+	cacheDir, args := extractStringFlag(args, "cache-dir", cacheDefaultDir())
+	noCache, args := extractBoolFlag(args, "no-cache")
+	resumeJobID, args := extractStringFlag(args, "resume", "")
+
+	// `transcribe` is a separate subcommand: it closes the loop by turning
+	// audio back into text instead of generating a podcast.
+	if len(args) > 0 && args[0] == "transcribe" {
+		runTranscribe(args[1:])
+		return
+	}
+
+	// `cache` manages the on-disk chunk cache (currently just `cache gc`).
+	if len(args) > 0 && args[0] == "cache" {
+		runCache(args[1:], cacheDir)
+		return
+	}
+
+	// `serve` exposes an OpenAI-compatible HTTP API instead of running the
+	// interactive CLI flow once.
+	if len(args) > 0 && args[0] == "serve" {
+		backend, err := newTTSBackend(backendName)
+		if err != nil {
+			fmt.Printf("\n❌ %v\n", err)
+			os.Exit(1)
+		}
+		runServe(args[1:], backend, cacheDir, noCache)
+		return
+	}
+
+	fmt.Println("=== Text-to-Speech Generator ===")
+
+	backend, err := newTTSBackend(backendName)
+	if err != nil {
+		fmt.Printf("\n❌ %v\n", err)
+		os.Exit(1)
+	}
+
+	// The OpenAI backend's API key is additionally validated against the
+	// live API up front; other backends fail fast on missing config instead.
+	if backendName == "" || backendName == defaultTTSBackend {
+		fmt.Println("Checking OpenAI API key...")
+		if err := checkAPIKey(); err != nil {
+			fmt.Printf("\n❌ %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Println("✅ API key valid")
+	}
+
+	// Vérifier si un fichier a été passé en argument
+	var promptData PromptData
+	if len(args) > 0 {
+		textFile := args[0]
+		// Vérifier si le fichier existe
+		if _, err := os.Stat(textFile); os.IsNotExist(err) {
+			fmt.Printf("❌ File not found: %s\n", textFile)
+			os.Exit(1)
+		}
+		// Collecter uniquement les autres paramètres
+		promptData = collectUserInputWithFile(textFile, backend.Voices())
+	} else {
+		// Collecter tous les paramètres via l'interface interactive
+		promptData = collectUserInput(backend.Voices())
+	}
+
+	// 2. Recap the information
+	fmt.Println("\nRecap:")
+	fmt.Printf("  Text File : %s\n", promptData.TextFile)
+	fmt.Printf("  Voice    : %s\n", promptData.Voice)
+	fmt.Printf("  Language : %s\n", promptData.Language)
+
+	// 3. Load text from file
+	fmt.Println("\nLoading text from file...")
+	content, err := os.ReadFile(promptData.TextFile)
+	if err != nil {
+		fmt.Printf("\n❌ Error reading file: %v\n", err)
+		os.Exit(1)
+	}
+	generatedText := string(content)
+	fmt.Printf("✅ Text loaded (%d characters)\n", len(generatedText))
+
+	// 4. Split the text into chunks (<= 2800 chars)
+	fmt.Println("\nSplitting text into chunks ...")
+	var chunks []string
+	if isSSMLText(generatedText) {
+		fmt.Println("Detected SSML input.")
+		chunks = splitSSMLIntoChunks(generatedText, 2800)
+	} else {
+		chunks = splitTextIntoChunks(generatedText, 2800)
+	}
+	fmt.Printf("Created %d chunk(s).\n", len(chunks))
+
+	// 5. Set up a named job directory (./jobs/<jobid>/) instead of an
+	// anonymous temp dir, so a failed run can be resumed with --resume
+	// rather than losing all progress.
+	jobID := resumeJobID
+	if jobID == "" {
+		jobID = newJobID()
+	}
+	jobDir := filepath.Join("jobs", jobID)
+	if err := os.MkdirAll(jobDir, 0755); err != nil {
+		fmt.Printf("Error creating job directory: %v\n", err)
+		os.Exit(1)
+	}
+	manifest, err := loadOrCreateManifest(jobDir, jobID, chunks, promptData.Voice, promptData.Language, backend.Name(), backend.Model(), backend.Format(), resumeJobID != "")
+	if err != nil {
+		fmt.Printf("\n❌ %v\n", err)
+		os.Exit(1)
+	}
+	if resumeJobID != "" {
+		fmt.Printf("\nResuming job %s...\n", jobID)
+	} else {
+		fmt.Printf("\nStarting job %s (resume later with --resume %s if it's interrupted)\n", jobID, jobID)
+	}
+
+	// 6. Générer l'audio pour tous les chunks en parallèle
+	fmt.Println("Generating audio for all chunks in parallel...")
+	audioFiles, err := generateTTSAudioParallel(backend, chunks, promptData.Voice, promptData.Language, jobDir, cacheDir, noCache, manifest)
+	if err != nil {
+		fmt.Printf("\n❌ Error during audio generation: %v\n", err)
+		os.Exit(1)
+	}
+	fmt.Println("\n✅ Audio generation complete.")
+
+	// 7. Concatenate all partial audio files. Which merger runs depends on
+	// the container the backend actually produces (MP3 frames vs. WAV PCM).
+	finalOutput := "podcast_final." + backend.Format()
+	fmt.Printf("\nAssembling chunks into final file: %s\n", finalOutput)
+	switch backend.Format() {
+	case "wav":
+		err = concatenateWAVFiles(audioFiles, finalOutput)
+	default:
+		err = concatenateMP3Files(audioFiles, finalOutput)
+	}
+	if err != nil {
+		fmt.Printf("Error assembling final audio: %v\n", err)
+		os.Exit(1)
+	}
+
+	fmt.Println("✅ Audio assembly complete!")
+	fmt.Printf("Your final audio is saved as '%s'\n", finalOutput)
+}
+
+// getValidChoice displays options and gets a valid choice from user
+func getValidChoice(reader *bufio.Reader, options []string, prompt string) string {
+	for {
+		fmt.Printf("\nAvailable %s:\n", prompt)
+		for i, opt := range options {
+			fmt.Printf("%d. %s\n", i+1, opt)
+		}
+		fmt.Print("Enter your choice (number): ")
+		input, _ := reader.ReadString('\n')
+		input = strings.TrimSpace(input)
+
+		if idx, err := strconv.Atoi(input); err == nil && idx > 0 && idx <= len(options) {
+			return options[idx-1]
+		}
+		fmt.Println("Invalid choice. Please try again.")
+	}
+}
+
+// collectUserInput asks the user (via console) for the required inputs.
+// voices is sourced from the active TTSBackend rather than hardcoded.
+func collectUserInput(voices []string) PromptData {
+	reader := bufio.NewReader(os.Stdin)
+
+	fmt.Print("Enter the path to your text file: ")
+	textFile, _ := reader.ReadString('\n')
+	textFile = strings.TrimSpace(textFile)
+
+	// Vérifier si le fichier existe
+	if _, err := os.Stat(textFile); os.IsNotExist(err) {
+		fmt.Printf("❌ File not found: %s\n", textFile)
+		os.Exit(1)
+	}
+
+	voice := getValidChoice(reader, voices, "voices")
+	language := getValidChoice(reader, availableLanguages, "languages")
+
+	return PromptData{
+		TextFile: textFile,
+		Voice:    voice,
+		Language: language,
+	}
+}
+
+// Nouvelle fonction pour collecter les entrées avec fichier prédéfini
+func collectUserInputWithFile(textFile string, voices []string) PromptData {
+	reader := bufio.NewReader(os.Stdin)
+	voice := getValidChoice(reader, voices, "voices")
+	language := getValidChoice(reader, availableLanguages, "languages")
+
+	return PromptData{
+		TextFile: textFile,
+		Voice:    voice,
+		Language: language,
+	}
+}
+
+// Improved chunk splitting to avoid losing text
+func splitTextIntoChunks(text string, maxSize int) []string {
+	sentences := splitIntoSentences(text)
+	var chunks []string
+	var currentChunk strings.Builder
+
+	for _, sentence := range sentences {
+		sentence = strings.TrimSpace(sentence)
+		if sentence == "" {
+			continue
+		}
+
+		// If this sentence alone exceeds maxSize, split it into smaller parts
+		if len(sentence) > maxSize {
+			if currentChunk.Len() > 0 {
+				chunks = append(chunks, currentChunk.String())
+				currentChunk.Reset()
+			}
+			// Split long sentence into smaller chunks while preserving words
+			words := strings.Fields(sentence)
+			var partialSentence strings.Builder
+			for _, word := range words {
+				if partialSentence.Len()+len(word)+1 > maxSize {
+					if partialSentence.Len() > 0 {
+						chunks = append(chunks, partialSentence.String())
+						partialSentence.Reset()
+					}
+				}
+				if partialSentence.Len() > 0 {
+					partialSentence.WriteString(" ")
+				}
+				partialSentence.WriteString(word)
+			}
+			if partialSentence.Len() > 0 {
+				chunks = append(chunks, partialSentence.String())
+			}
+			continue
+		}
+
+		// Normal sentence processing
+		if currentChunk.Len()+len(sentence)+1 > maxSize {
+			chunks = append(chunks, currentChunk.String())
+			currentChunk.Reset()
+		}
+
+		if currentChunk.Len() > 0 {
+			currentChunk.WriteString(" ")
+		}
+		currentChunk.WriteString(sentence)
+	}
+
+	// Don't forget the last chunk
+	if currentChunk.Len() > 0 {
+		chunks = append(chunks, currentChunk.String())
+	}
+
+	return chunks
+}
+
+// sentenceEndRunes are punctuation marks that terminate a sentence across
+// the scripts this tool supports: Latin/Cyrillic . ? !, CJK full-width
+// 。！？, Devanagari danda ।॥, and the Arabic question mark ؟.
+var sentenceEndRunes = map[rune]bool{
+	'.': true, '?': true, '!': true,
+	'。': true, '！': true, '？': true, // 。！？
+	'।': true, '॥': true, // ।॥
+	'؟': true, // ؟
+}
+
+// commonAbbreviations are short, dotted tokens that must not be treated as
+// sentence boundaries on their own, even though they end in '.'.
+var commonAbbreviations = map[string]bool{
+	"mr.": true, "mrs.": true, "ms.": true, "dr.": true, "prof.": true,
+	"sr.": true, "jr.": true, "st.": true, "vs.": true, "etc.": true,
+	"e.g.": true, "i.e.": true, "fig.": true, "no.": true, "approx.": true,
+}
+
+// isOpenQuoteRune and isCloseQuoteRune only cover quote marks that are
+// unambiguously directional (curly quotes, guillemets). Straight `"` is
+// ambiguous — the same rune opens and closes — so splitIntoSentences
+// tracks it separately via an odd/even toggle instead of routing it
+// through these.
+func isOpenQuoteRune(r rune) bool {
+	return r == '“' || r == '«' || r == '„'
+}
+
+func isCloseQuoteRune(r rune) bool {
+	return r == '”' || r == '»'
+}
+
+// isDigit reports whether r is an ASCII digit.
+func isDigit(r rune) bool {
+	return r >= '0' && r <= '9'
+}
+
+// lastWord returns the trailing whitespace-delimited token of s, used to
+// check it against commonAbbreviations.
+func lastWord(s string) string {
+	fields := strings.Fields(s)
+	if len(fields) == 0 {
+		return ""
+	}
+	return fields[len(fields)-1]
+}
+
+// looksLikeURL reports whether s ends in what appears to be an in-progress
+// URL, so a "." inside e.g. "example.com" isn't mistaken for a sentence
+// boundary.
+func looksLikeURL(s string) bool {
+	word := lastWord(s)
+	return strings.Contains(word, "://") || strings.HasPrefix(word, "www.")
+}
+
+// isSentenceBoundaryFollower reports whether r can legitimately follow a
+// sentence-ending mark: real boundaries are followed by whitespace or a
+// closing quote/paren, never directly by another letter or digit.
+func isSentenceBoundaryFollower(r rune) bool {
+	return unicode.IsSpace(r) || isCloseQuoteRune(r) || r == '"' || r == ')' || r == '）'
+}
+
+// splitIntoSentences segments text into sentences. Unlike a naive split on
+// ".", "?", "!", it understands CJK/Devanagari/Arabic terminators and
+// refuses to split inside decimal numbers, URLs, abbreviations, or
+// quoted/parenthetical spans.
+func splitIntoSentences(text string) []string {
+	runes := []rune(text)
+	var sentences []string
+	var current strings.Builder
+
+	quoteDepth := 0
+	parenDepth := 0
+	// straightQuoteOpen tracks `"`, which (unlike curly quotes) can't tell
+	// open from close by rune alone: true after an odd number of `"` seen
+	// so far, false after an even number.
+	straightQuoteOpen := false
+
+	for i := 0; i < len(runes); i++ {
+		r := runes[i]
+		current.WriteRune(r)
+
+		switch {
+		case r == '"':
+			straightQuoteOpen = !straightQuoteOpen
+			continue
+		case isOpenQuoteRune(r):
+			quoteDepth++
+			continue
+		case isCloseQuoteRune(r) && quoteDepth > 0:
+			quoteDepth--
+			continue
+		case r == '(' || r == '（':
+			parenDepth++
+			continue
+		case r == ')' || r == '）':
+			if parenDepth > 0 {
+				parenDepth--
+			}
+			continue
+		}
+
+		if !sentenceEndRunes[r] || quoteDepth > 0 || parenDepth > 0 || straightQuoteOpen {
+			continue
+		}
+
+		// Decimal point: digit before and after ("3.14") stays together.
+		if r == '.' && i > 0 && i+1 < len(runes) && isDigit(runes[i-1]) && isDigit(runes[i+1]) {
+			continue
+		}
+
+		// Inside a URL, "." doesn't end a sentence.
+		if looksLikeURL(current.String()) {
+			continue
+		}
+
+		// Known abbreviation: this "." is part of the token, not a boundary.
+		if r == '.' && commonAbbreviations[strings.ToLower(lastWord(current.String()))] {
+			continue
+		}
+
+		// For ASCII ".", require whitespace/quote/paren next: CJK and
+		// Devanagari terminators are conventionally followed directly by the
+		// next sentence with no space, but a bare "." followed by a letter
+		// or digit is almost always mid-word (abbreviation, decimal, URL).
+		if r == '.' && i+1 < len(runes) && !isSentenceBoundaryFollower(runes[i+1]) {
+			continue
+		}
+
+		sentences = append(sentences, strings.TrimSpace(current.String()))
+		current.Reset()
+	}
+
+	if current.Len() > 0 {
+		sentences = append(sentences, strings.TrimSpace(current.String()))
+	}
+
+	return sentences
+}
+
+// isSSMLText reports whether text is an SSML document (i.e. has a <speak>
+// root) rather than plain text.
+func isSSMLText(text string) bool {
+	return strings.HasPrefix(strings.ToLower(strings.TrimSpace(text)), "<speak")
+}
+
+// splitSSMLIntoChunks splits an SSML document into chunks of at most
+// maxSize bytes, never cutting inside an element: it walks the document
+// tracking nesting depth relative to the <speak> root and only cuts at
+// offsets where depth is back down to a direct child of <speak>, so
+// <break>, <prosody>, and <voice> spans always stay intact. Each chunk is
+// re-wrapped in its own <speak> so it remains valid SSML on its own.
+func splitSSMLIntoChunks(text string, maxSize int) []string {
+	trimmed := strings.TrimSpace(text)
+	decoder := xml.NewDecoder(strings.NewReader(trimmed))
+	decoder.Strict = false
+
+	var chunks []string
+	depth := 0
+	chunkStart := -1
+	lastSafeEnd := -1
+
+	flush := func(end int) {
+		if chunkStart < 0 || end <= chunkStart {
+			return
+		}
+		inner := strings.TrimSpace(trimmed[chunkStart:end])
+		if inner != "" {
+			chunks = append(chunks, "<speak>"+inner+"</speak>")
+		}
+	}
+
+	for {
+		tok, err := decoder.Token()
+		if err != nil {
+			break
+		}
+
+		switch t := tok.(type) {
+		case xml.StartElement:
+			if t.Name.Local == "speak" && depth == 0 {
+				depth = 1
+				chunkStart = int(decoder.InputOffset())
+				lastSafeEnd = chunkStart
+				continue
+			}
+			depth++
+		case xml.EndElement:
+			if t.Name.Local == "speak" {
+				flush(lastSafeEnd)
+				depth = 0
+				continue
+			}
+			depth--
+			if depth == 1 {
+				lastSafeEnd = int(decoder.InputOffset())
+			}
+		case xml.CharData:
+			if depth == 1 {
+				lastSafeEnd = int(decoder.InputOffset())
+			}
+		}
+
+		if depth == 1 && chunkStart >= 0 && lastSafeEnd-chunkStart >= maxSize {
+			flush(lastSafeEnd)
+			chunkStart = lastSafeEnd
+		}
+	}
+
+	flush(lastSafeEnd)
+
+	if len(chunks) == 0 {
+		return []string{trimmed}
+	}
+	return chunks
+}
+
+// generateTTSAudio resolves textChunk's audio (via the cache or backend,
+// retrying transient failures, see withBackendRetry) and saves it to the
+// provided outputFile path.
+func generateTTSAudio(ctx context.Context, backend TTSBackend, textChunk, voice, lang, outputFile, cacheDir string, noCache bool) error {
+	audio, err := withBackendRetry(ctx, backend, textChunk, voice, lang, cacheDir, noCache)
+	if err != nil {
+		return err
+	}
+
+	if err := os.WriteFile(outputFile, audio, 0644); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// runTranscribe implements the `transcribe` subcommand: it sends an audio
+// file to OpenAI's Whisper endpoint and writes the resulting text next to
+// it, so the transcript can be fed back into the TTS pipeline for
+// translation-style round trips.
+func runTranscribe(args []string) {
+	fmt.Println("=== Audio Transcription ===")
+
+	fmt.Println("Checking OpenAI API key...")
+	if err := checkAPIKey(); err != nil {
+		fmt.Printf("\n❌ %v\n", err)
+		os.Exit(1)
+	}
+	fmt.Println("✅ API key valid")
+
+	if len(args) < 1 {
+		fmt.Println("❌ Usage: autopodcastgpt transcribe <audio-file>")
+		os.Exit(1)
+	}
+	audioFile := args[0]
+	if _, err := os.Stat(audioFile); os.IsNotExist(err) {
+		fmt.Printf("❌ File not found: %s\n", audioFile)
+		os.Exit(1)
+	}
+
+	reader := bufio.NewReader(os.Stdin)
+	language := getValidChoice(reader, availableLanguages, "languages")
+
+	fmt.Println("\nTranscribing audio...")
+	text, err := transcribeAudio(audioFile, language)
+	if err != nil {
+		fmt.Printf("\n❌ %v\n", err)
+		os.Exit(1)
+	}
+
+	outputFile := strings.TrimSuffix(filepath.Base(audioFile), filepath.Ext(audioFile)) + ".txt"
+	if err := os.WriteFile(outputFile, []byte(text), 0644); err != nil {
+		fmt.Printf("\n❌ Error writing transcript: %v\n", err)
+		os.Exit(1)
+	}
+
+	fmt.Println("\n✅ Transcription complete.")
+	fmt.Printf("Transcript saved as '%s'\n", outputFile)
+}
+
+// transcribeAudio sends audioFile to OpenAI's Whisper transcription endpoint
+// and returns the resulting text. language is passed as a prompt hint since
+// Whisper has no dedicated "expected language" field for free-form names
+// like the ones in availableLanguages.
+func transcribeAudio(audioFile, language string) (string, error) {
+	apiKey := os.Getenv("OPENAI_API_KEY")
+	if apiKey == "" {
+		return "", &PodcastError{
+			Stage:   "transcription",
+			Message: "API key not found",
+		}
+	}
+
+	file, err := os.Open(audioFile)
+	if err != nil {
+		return "", &PodcastError{
+			Stage:   "transcription",
+			Message: "Failed to open audio file",
+			Err:     err,
+		}
+	}
+	defer file.Close()
+
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+
+	part, err := writer.CreateFormFile("file", filepath.Base(audioFile))
+	if err != nil {
+		return "", err
+	}
+	if _, err := io.Copy(part, file); err != nil {
+		return "", err
+	}
+	if err := writer.WriteField("model", OpenAIModelTranscription); err != nil {
+		return "", err
+	}
+	if err := writer.WriteField("prompt", fmt.Sprintf("The audio is in %s.", language)); err != nil {
+		return "", err
+	}
+	if err := writer.Close(); err != nil {
+		return "", err
+	}
+
+	req, err := http.NewRequest("POST", OpenAITranscriptionEndpoint, &body)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Authorization", "Bearer "+apiKey)
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+
+	client := &http.Client{}
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		fmt.Printf("\n🔍 OpenAI API Response:\n%s\n", string(respBody))
+		return "", &PodcastError{
+			Stage:   "transcription",
+			Message: fmt.Sprintf("transcription request failed (status %d)", resp.StatusCode),
+			Err:     fmt.Errorf(string(respBody)),
+		}
+	}
+
+	var result struct {
+		Text string `json:"text"`
+	}
+	if err := json.Unmarshal(respBody, &result); err != nil {
+		return "", &PodcastError{
+			Stage:   "transcription",
+			Message: "Failed to parse transcription response",
+			Err:     err,
+		}
+	}
+
+	return result.Text, nil
+}
+
+// mp3FrameHeader captures the decoded fields of an MPEG audio frame header,
+// enough to validate frame boundaries, detect Xing/Info headers, and rebuild
+// a new header when synthesizing the final Xing frame.
+type mp3FrameHeader struct {
+	VersionBits     byte
+	LayerBits       byte
+	BitrateIndex    byte
+	SampleRateIndex byte
+	Padding         int
+	ChannelMode     int
+	BitrateKbps     int
+	SampleRate      int
+	FrameSize       int
+}
+
+// mp3BitrateKbps maps [MPEG1 or MPEG2/2.5][Layer I/II/III][bitrate index] to
+// the bitrate in kbps. A value of -1 marks a free or reserved bitrate.
+var mp3BitrateKbps = [2][3][16]int{
+	{ // MPEG1
+		{0, 32, 64, 96, 128, 160, 192, 224, 256, 288, 320, 352, 384, 416, 448, -1}, // Layer I
+		{0, 32, 48, 56, 64, 80, 96, 112, 128, 160, 192, 224, 256, 320, 384, -1},    // Layer II
+		{0, 32, 40, 48, 56, 64, 80, 96, 112, 128, 160, 192, 224, 256, 320, -1},     // Layer III
+	},
+	{ // MPEG2 / MPEG2.5
+		{0, 32, 48, 56, 64, 80, 96, 112, 128, 144, 160, 176, 192, 224, 256, -1}, // Layer I
+		{0, 8, 16, 24, 32, 40, 48, 56, 64, 80, 96, 112, 128, 144, 160, -1},      // Layer II
+		{0, 8, 16, 24, 32, 40, 48, 56, 64, 80, 96, 112, 128, 144, 160, -1},      // Layer III
+	},
+}
+
+// mp3SampleRateHz maps [MPEG1/MPEG2/MPEG2.5][sample rate index] to Hz.
+var mp3SampleRateHz = [3][3]int{
+	{44100, 48000, 32000}, // MPEG1
+	{22050, 24000, 16000}, // MPEG2
+	{11025, 12000, 8000},  // MPEG2.5
+}
+
+// parseMP3FrameHeader decodes the 4-byte MPEG audio frame header at the start
+// of b and computes the resulting frame size in bytes (header + payload).
+func parseMP3FrameHeader(b []byte) (*mp3FrameHeader, error) {
+	if len(b) < 4 {
+		return nil, fmt.Errorf("truncated frame header")
+	}
+	if b[0] != 0xFF || b[1]&0xE0 != 0xE0 {
+		return nil, fmt.Errorf("missing frame sync")
+	}
+
+	versionBits := (b[1] >> 3) & 0x03
+	layerBits := (b[1] >> 1) & 0x03
+	bitrateIndex := (b[2] >> 4) & 0x0F
+	sampleRateIndex := (b[2] >> 2) & 0x03
+	padding := int((b[2] >> 1) & 0x01)
+	channelMode := int((b[3] >> 6) & 0x03)
+
+	if versionBits == 0x01 || layerBits == 0x00 || sampleRateIndex == 0x03 {
+		return nil, fmt.Errorf("reserved header field")
+	}
+
+	var sampleRateVersion, bitrateVersion int
+	switch versionBits {
+	case 0x03:
+		sampleRateVersion, bitrateVersion = 0, 0 // MPEG1
+	case 0x02:
+		sampleRateVersion, bitrateVersion = 1, 1 // MPEG2
+	case 0x00:
+		sampleRateVersion, bitrateVersion = 2, 1 // MPEG2.5
+	}
+
+	var layerIndex int
+	switch layerBits {
+	case 0x03:
+		layerIndex = 0 // Layer I
+	case 0x02:
+		layerIndex = 1 // Layer II
+	case 0x01:
+		layerIndex = 2 // Layer III
+	}
+
+	bitrateKbps := mp3BitrateKbps[bitrateVersion][layerIndex][bitrateIndex]
+	if bitrateKbps <= 0 {
+		return nil, fmt.Errorf("free or reserved bitrate")
+	}
+	sampleRate := mp3SampleRateHz[sampleRateVersion][sampleRateIndex]
+
+	var frameSize int
+	if layerIndex == 0 {
+		frameSize = (12*bitrateKbps*1000/sampleRate + padding) * 4
+	} else {
+		frameSize = 144*bitrateKbps*1000/sampleRate + padding
+	}
+	if frameSize <= 4 {
+		return nil, fmt.Errorf("invalid frame size")
+	}
+
+	return &mp3FrameHeader{
+		VersionBits:     versionBits,
+		LayerBits:       layerBits,
+		BitrateIndex:    bitrateIndex,
+		SampleRateIndex: sampleRateIndex,
+		Padding:         padding,
+		ChannelMode:     channelMode,
+		BitrateKbps:     bitrateKbps,
+		SampleRate:      sampleRate,
+		FrameSize:       frameSize,
+	}, nil
+}
+
+// skipLeadingID3v2 returns the number of bytes to skip over a leading ID3v2
+// tag, or 0 if data doesn't start with one.
+func skipLeadingID3v2(data []byte) int {
+	if len(data) < 10 || string(data[0:3]) != "ID3" {
+		return 0
+	}
+	size := int(data[6]&0x7F)<<21 | int(data[7]&0x7F)<<14 | int(data[8]&0x7F)<<7 | int(data[9]&0x7F)
+	return 10 + size
+}
+
+// hasTrailingID3v1 reports whether data ends with a 128-byte ID3v1 tag.
+func hasTrailingID3v1(data []byte) bool {
+	return len(data) >= 128 && string(data[len(data)-128:len(data)-125]) == "TAG"
+}
+
+// xingTagOffset returns the byte offset (from the start of the frame) where
+// a Xing/Info header stores its tag, which depends on the MPEG version and
+// channel mode since both affect the size of the side info that precedes it.
+func xingTagOffset(hdr *mp3FrameHeader) int {
+	isMPEG1 := hdr.VersionBits == 0x03
+	isMono := hdr.ChannelMode == 3
+	switch {
+	case isMPEG1 && !isMono:
+		return 36
+	case isMPEG1 && isMono:
+		return 21
+	case !isMPEG1 && !isMono:
+		return 21
+	default:
+		return 13
+	}
+}
+
+// isXingFrame reports whether frame is a Xing/Info header frame rather than
+// actual audio. Such frames describe the chunk they were encoded for and
+// must not be spliced into the middle of the merged stream.
+func isXingFrame(frame []byte, hdr *mp3FrameHeader) bool {
+	off := xingTagOffset(hdr)
+	if off+4 > len(frame) {
+		return false
+	}
+	tag := string(frame[off : off+4])
+	return tag == "Xing" || tag == "Info"
+}
+
+// buildXingHeaderFrame synthesizes a new leading Xing frame that reports the
+// total number of audio frames in the merged file, so players can report an
+// accurate duration and seek correctly.
+func buildXingHeaderFrame(ref *mp3FrameHeader, totalFrames int) []byte {
+	frame := make([]byte, ref.FrameSize)
+
+	frame[0] = 0xFF
+	frame[1] = 0xE0 | ref.VersionBits<<3 | ref.LayerBits<<1 | 0x01 // no CRC
+	frame[2] = ref.BitrateIndex<<4 | ref.SampleRateIndex<<2 | byte(ref.Padding<<1)
+	frame[3] = byte(ref.ChannelMode << 6)
+
+	off := xingTagOffset(ref)
+	copy(frame[off:off+4], "Xing")
+	binary.BigEndian.PutUint32(frame[off+4:off+8], 0x00000001) // FRAMES field present
+	binary.BigEndian.PutUint32(frame[off+8:off+12], uint32(totalFrames))
+
+	return frame
+}
+
+// extractMP3Frames walks one chunk's raw MP3 bytes frame by frame, dropping
+// ID3v1/ID3v2 tags and any Xing/Info header (which describes only that
+// chunk). *reference is set from the first frame seen across all calls and
+// checked against on every later one, so sample rate/channel mode
+// inconsistencies across chunks are caught regardless of whether the
+// caller is buffering a whole file (concatenateMP3Files) or forwarding
+// frames to an HTTP response as they arrive (streamChunksInOrder).
+func extractMP3Frames(data []byte, reference **mp3FrameHeader) ([][]byte, error) {
+	offset := skipLeadingID3v2(data)
+	end := len(data)
+	if hasTrailingID3v1(data) {
+		end -= 128
+	}
+
+	var frames [][]byte
+	firstFrame := true
+	for offset < end {
+		hdr, err := parseMP3FrameHeader(data[offset:end])
+		if err != nil {
+			return nil, fmt.Errorf("invalid MP3 frame at offset %d: %w", offset, err)
+		}
+		if offset+hdr.FrameSize > end {
+			return nil, fmt.Errorf("truncated MP3 frame at offset %d", offset)
+		}
+		frame := data[offset : offset+hdr.FrameSize]
+		offset += hdr.FrameSize
+
+		if firstFrame {
+			firstFrame = false
+			if isXingFrame(frame, hdr) {
+				// Every chunk gets its own Xing/Info header describing only
+				// that chunk; drop them all and rebuild one for the whole
+				// podcast in concatenateMP3Files (streaming omits it, since
+				// the total frame count isn't known until the stream ends).
+				continue
+			}
+		}
+
+		if *reference == nil {
+			*reference = hdr
+		} else if hdr.SampleRate != (*reference).SampleRate || hdr.ChannelMode != (*reference).ChannelMode {
+			return nil, fmt.Errorf("inconsistent sample rate/channel mode (got %dHz mode %d, expected %dHz mode %d)",
+				hdr.SampleRate, hdr.ChannelMode, (*reference).SampleRate, (*reference).ChannelMode)
+		}
 
-	return PromptData{
-		TextFile: textFile,
-		Voice:    voice,
-		Language: language,
+		frames = append(frames, frame)
 	}
+
+	return frames, nil
 }
 
-// Improved chunk splitting to avoid losing text
-func splitTextIntoChunks(text string, maxSize int) []string {
-	sentences := splitIntoSentences(text)
-	var chunks []string
-	var currentChunk strings.Builder
+// concatenateMP3Files merges multiple partial MP3 files into a single,
+// well-formed MP3. Rather than a raw byte copy, it walks each file frame by
+// frame via extractMP3Frames, checks that sample rate and channel mode stay
+// consistent across chunks, and prepends a fresh Xing header describing the
+// total frame count.
+func concatenateMP3Files(files []string, output string) error {
+	var reference *mp3FrameHeader
+	var audioFrames [][]byte
 
-	for _, sentence := range sentences {
-		sentence = strings.TrimSpace(sentence)
-		if sentence == "" {
-			continue
+	for _, f := range files {
+		data, err := os.ReadFile(f)
+		if err != nil {
+			return &PodcastError{
+				Stage:   "audio assembly",
+				Message: fmt.Sprintf("failed to read chunk %q", f),
+				Err:     err,
+			}
 		}
 
-		// If this sentence alone exceeds maxSize, split it into smaller parts
-		if len(sentence) > maxSize {
-			if currentChunk.Len() > 0 {
-				chunks = append(chunks, currentChunk.String())
-				currentChunk.Reset()
-			}
-			// Split long sentence into smaller chunks while preserving words
-			words := strings.Fields(sentence)
-			var partialSentence strings.Builder
-			for _, word := range words {
-				if partialSentence.Len()+len(word)+1 > maxSize {
-					if partialSentence.Len() > 0 {
-						chunks = append(chunks, partialSentence.String())
-						partialSentence.Reset()
-					}
-				}
-				if partialSentence.Len() > 0 {
-					partialSentence.WriteString(" ")
-				}
-				partialSentence.WriteString(word)
-			}
-			if partialSentence.Len() > 0 {
-				chunks = append(chunks, partialSentence.String())
+		frames, err := extractMP3Frames(data, &reference)
+		if err != nil {
+			return &PodcastError{
+				Stage:   "audio assembly",
+				Message: fmt.Sprintf("invalid MP3 chunk %q", f),
+				Err:     err,
 			}
-			continue
 		}
 
-		// Normal sentence processing
-		if currentChunk.Len()+len(sentence)+1 > maxSize {
-			chunks = append(chunks, currentChunk.String())
-			currentChunk.Reset()
-		}
+		audioFrames = append(audioFrames, frames...)
+	}
 
-		if currentChunk.Len() > 0 {
-			currentChunk.WriteString(" ")
+	if reference == nil {
+		return &PodcastError{
+			Stage:   "audio assembly",
+			Message: "no valid MP3 frames found across chunks",
 		}
-		currentChunk.WriteString(sentence)
 	}
 
-	// Don't forget the last chunk
-	if currentChunk.Len() > 0 {
-		chunks = append(chunks, currentChunk.String())
+	outFile, err := os.Create(output)
+	if err != nil {
+		return err
 	}
+	defer outFile.Close()
 
-	return chunks
+	if _, err := outFile.Write(buildXingHeaderFrame(reference, len(audioFrames))); err != nil {
+		return err
+	}
+	for _, frame := range audioFrames {
+		if _, err := outFile.Write(frame); err != nil {
+			return err
+		}
+	}
+
+	return nil
 }
 
-// splitIntoSentences splits text into sentences using basic punctuation rules
-func splitIntoSentences(text string) []string {
-	separators := []string{".", "?", "!"}
-	var replacerStr = text
+// wavFormat holds the subset of a WAV file's fmt chunk needed to verify
+// chunks are compatible before concatenating them.
+type wavFormat struct {
+	AudioFormat   uint16
+	NumChannels   uint16
+	SampleRate    uint32
+	BitsPerSample uint16
+}
 
-	for _, sep := range separators {
-		replacerStr = strings.ReplaceAll(replacerStr, sep, sep+"|SEP|")
+// parseWAVChunks walks data's RIFF chunk list looking for "fmt " and "data",
+// returning the format and the data payload. A missing RIFF/WAVE header or
+// either chunk is an error.
+func parseWAVChunks(data []byte) (*wavFormat, []byte, error) {
+	if len(data) < 12 || string(data[0:4]) != "RIFF" || string(data[8:12]) != "WAVE" {
+		return nil, nil, fmt.Errorf("missing RIFF/WAVE header")
 	}
 
-	parts := strings.Split(replacerStr, "|SEP|")
+	var format *wavFormat
+	var payload []byte
 
-	for i, p := range parts {
-		parts[i] = strings.TrimSpace(p)
-	}
-	return parts
-}
+	offset := 12
+	for offset+8 <= len(data) {
+		id := string(data[offset : offset+4])
+		size := int(binary.LittleEndian.Uint32(data[offset+4 : offset+8]))
+		body := offset + 8
+		if size < 0 || body+size > len(data) {
+			return nil, nil, fmt.Errorf("truncated %q chunk", id)
+		}
 
-// generateTTSAudio calls the TTS endpoint with given text chunk and
-// saves the resulting MP3 to the provided outputFile path
-func generateTTSAudio(textChunk, voice, outputFile string) error {
-	if len(strings.TrimSpace(textChunk)) == 0 {
-		return &PodcastError{
-			Stage:   "audio generation",
-			Message: "Empty text chunk",
+		switch id {
+		case "fmt ":
+			if size < 16 {
+				return nil, nil, fmt.Errorf("fmt chunk too small")
+			}
+			format = &wavFormat{
+				AudioFormat:   binary.LittleEndian.Uint16(data[body : body+2]),
+				NumChannels:   binary.LittleEndian.Uint16(data[body+2 : body+4]),
+				SampleRate:    binary.LittleEndian.Uint32(data[body+4 : body+8]),
+				BitsPerSample: binary.LittleEndian.Uint16(data[body+14 : body+16]),
+			}
+		case "data":
+			payload = data[body : body+size]
 		}
-	}
 
-	apiKey := os.Getenv("OPENAI_API_KEY")
-	if apiKey == "" {
-		return &PodcastError{
-			Stage:   "audio generation",
-			Message: "API key not found",
+		offset = body + size
+		if size%2 == 1 { // chunks are word-aligned
+			offset++
 		}
 	}
 
-	payload := map[string]interface{}{
-		"model": OpenAIModelTTS,
-		"input": textChunk,
-		"voice": voice,
+	if format == nil {
+		return nil, nil, fmt.Errorf("missing fmt chunk")
 	}
-	jsonData, err := json.Marshal(payload)
-	if err != nil {
-		return err
+	if payload == nil {
+		return nil, nil, fmt.Errorf("missing data chunk")
 	}
+	return format, payload, nil
+}
 
-	req, err := http.NewRequest("POST", OpenAITTSEndpoint, bytes.NewBuffer(jsonData))
-	if err != nil {
-		return err
+// buildWAVHeader returns a canonical 44-byte PCM WAV header for dataSize
+// bytes of audio in the given format. A negative dataSize means the total
+// size isn't known yet (streaming audio whose last chunk hasn't arrived);
+// the RIFF and data sizes are set to 0xFFFFFFFF, the conventional "unknown
+// length" marker most players tolerate for live/streamed WAV.
+func buildWAVHeader(format *wavFormat, dataSize int) []byte {
+	header := make([]byte, 44)
+	riffSize, dataSizeField := uint32(0xFFFFFFFF), uint32(0xFFFFFFFF)
+	if dataSize >= 0 {
+		riffSize = uint32(36 + dataSize)
+		dataSizeField = uint32(dataSize)
 	}
-	req.Header.Set("Authorization", "Bearer "+apiKey)
-	req.Header.Set("Content-Type", "application/json")
 
-	client := &http.Client{}
-	resp, err := client.Do(req)
-	if err != nil {
-		return err
-	}
+	copy(header[0:4], "RIFF")
+	binary.LittleEndian.PutUint32(header[4:8], riffSize)
+	copy(header[8:12], "WAVE")
+	copy(header[12:16], "fmt ")
+	binary.LittleEndian.PutUint32(header[16:20], 16)
+	binary.LittleEndian.PutUint16(header[20:22], format.AudioFormat)
+	binary.LittleEndian.PutUint16(header[22:24], format.NumChannels)
+	binary.LittleEndian.PutUint32(header[24:28], format.SampleRate)
+	byteRate := format.SampleRate * uint32(format.NumChannels) * uint32(format.BitsPerSample) / 8
+	binary.LittleEndian.PutUint32(header[28:32], byteRate)
+	blockAlign := format.NumChannels * format.BitsPerSample / 8
+	binary.LittleEndian.PutUint16(header[32:34], blockAlign)
+	binary.LittleEndian.PutUint16(header[34:36], format.BitsPerSample)
+	copy(header[36:40], "data")
+	binary.LittleEndian.PutUint32(header[40:44], dataSizeField)
+	return header
+}
 
-	defer resp.Body.Close()
+// concatenateWAVFiles is concatenateMP3Files's WAV counterpart, for
+// backends (Piper, LocalAI) that produce WAV instead of MP3. Each chunk's
+// own RIFF/fmt/data framing is stripped and only the raw PCM payloads are
+// spliced together behind a single corrected header — naively concatenating
+// whole WAV files would leave a stale header and chunk boundary in the
+// middle of the stream, corrupting playback past the first chunk.
+func concatenateWAVFiles(files []string, output string) error {
+	var reference *wavFormat
+	var payload bytes.Buffer
 
-	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
-		fmt.Printf("\n🔍 OpenAI API Response:\n%s\n", string(body))
-		return &PodcastError{
-			Stage:   "audio generation",
-			Message: fmt.Sprintf("TTS request failed (status %d)", resp.StatusCode),
-			Err:     fmt.Errorf(string(body)),
+	for _, f := range files {
+		data, err := os.ReadFile(f)
+		if err != nil {
+			return &PodcastError{
+				Stage:   "audio assembly",
+				Message: fmt.Sprintf("failed to read chunk %q", f),
+				Err:     err,
+			}
 		}
-	}
 
-	outFile, err := os.Create(outputFile)
-	if err != nil {
-		return err
-	}
+		format, chunkData, err := parseWAVChunks(data)
+		if err != nil {
+			return &PodcastError{
+				Stage:   "audio assembly",
+				Message: fmt.Sprintf("invalid WAV chunk %q", f),
+				Err:     err,
+			}
+		}
 
-	defer outFile.Close()
+		if reference == nil {
+			reference = format
+		} else if *format != *reference {
+			return &PodcastError{
+				Stage: "audio assembly",
+				Message: fmt.Sprintf("chunk %q has inconsistent WAV format (got %+v, expected %+v)",
+					f, *format, *reference),
+			}
+		}
 
-	_, err = io.Copy(outFile, resp.Body)
-	if err != nil {
-		return err
+		payload.Write(chunkData)
 	}
 
-	// Verify audio file size after creation
-	stat, err := outFile.Stat()
-	if err != nil || stat.Size() == 0 {
+	if reference == nil {
 		return &PodcastError{
-			Stage:   "audio generation",
-			Message: "Generated audio file is empty",
-			Err:     err,
+			Stage:   "audio assembly",
+			Message: "no valid WAV chunks found across chunks",
 		}
 	}
 
-	return nil
-}
-
-// concatenateMP3Files combines multiple MP3 files into a single output file
-func concatenateMP3Files(files []string, output string) error {
 	outFile, err := os.Create(output)
 	if err != nil {
 		return err
 	}
-
 	defer outFile.Close()
 
-	for _, f := range files {
-		inFile, err := os.Open(f)
-		if err != nil {
-			return err
-		}
-		_, copyErr := io.Copy(outFile, inFile)
-		inFile.Close()
-		if copyErr != nil {
-			return copyErr
-		}
+	if _, err := outFile.Write(buildWAVHeader(reference, payload.Len())); err != nil {
+		return err
 	}
-
-	return nil
+	_, err = outFile.Write(payload.Bytes())
+	return err
 }
 
 // showSpinner displays a loading animation during long operations
@@ -488,12 +1957,146 @@ func saveGeneratedText(text string) error {
 	return nil
 }
 
-// generateTTSAudioParallel gère la génération audio en parallèle
-func generateTTSAudioParallel(chunks []string, voice string, tmpDir string) ([]string, error) {
+// jobChunk status values recorded in a job's manifest.json.
+const (
+	jobChunkPending = "pending"
+	jobChunkDone    = "done"
+	jobChunkFailed  = "failed"
+)
+
+// jobChunk records one chunk's progress within a job.
+type jobChunk struct {
+	Index  int    `json:"index"`
+	Hash   string `json:"hash"`
+	File   string `json:"file"`
+	Status string `json:"status"`
+}
+
+// jobManifest is the on-disk record of a generation job under ./jobs/<id>/,
+// letting --resume skip chunks that already completed instead of
+// resynthesizing (and re-billing) an entire multi-hour run after one
+// transient failure.
+type jobManifest struct {
+	JobID    string     `json:"job_id"`
+	Backend  string     `json:"backend"`
+	Model    string     `json:"model"`
+	Format   string     `json:"format"`
+	Voice    string     `json:"voice"`
+	Language string     `json:"language"`
+	Chunks   []jobChunk `json:"chunks"`
+}
+
+// manifestPath returns the path to jobDir's manifest.json.
+func manifestPath(jobDir string) string {
+	return filepath.Join(jobDir, "manifest.json")
+}
+
+// newJobID generates a time-based identifier for a fresh (non-resumed) job.
+func newJobID() string {
+	return "job_" + time.Now().Format("20060102_150405")
+}
+
+// chunkTextHash hashes a chunk's normalized text, used to verify a resumed
+// job's manifest still matches the input it was created from.
+func chunkTextHash(text string) string {
+	sum := sha256.Sum256([]byte(normalizeTextForCache(text)))
+	return hex.EncodeToString(sum[:])
+}
+
+// saveManifest writes manifest to jobDir/manifest.json.
+func saveManifest(jobDir string, manifest *jobManifest) error {
+	data, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(manifestPath(jobDir), data, 0644)
+}
+
+// loadOrCreateManifest builds jobDir's manifest for a fresh job, or, when
+// resuming, loads the existing one and checks it still matches this run:
+// same backend/model/format/voice/language, and the same chunk count and
+// per-chunk text hash. Without this, picking a different voice, language,
+// or --backend on --resume would pair already-"done" chunks from the old
+// run with newly-retried chunks from the new one, silently mixing voices
+// mid-podcast (or, across a format change, handing the wrong container's
+// bytes to concatenateMP3Files/concatenateWAVFiles).
+func loadOrCreateManifest(jobDir, jobID string, chunks []string, voice, lang, backendName, model, format string, resuming bool) (*jobManifest, error) {
+	if resuming {
+		data, err := os.ReadFile(manifestPath(jobDir))
+		if err != nil {
+			return nil, &PodcastError{
+				Stage:   "job resume",
+				Message: fmt.Sprintf("no manifest found for job %q", jobID),
+				Err:     err,
+			}
+		}
+		var manifest jobManifest
+		if err := json.Unmarshal(data, &manifest); err != nil {
+			return nil, &PodcastError{
+				Stage:   "job resume",
+				Message: "failed to parse manifest.json",
+				Err:     err,
+			}
+		}
+		if manifest.Backend != backendName || manifest.Model != model || manifest.Format != format || manifest.Voice != voice || manifest.Language != lang {
+			return nil, &PodcastError{
+				Stage: "job resume",
+				Message: fmt.Sprintf(
+					"job %q was started with backend=%s model=%s format=%s voice=%s language=%s, but this run would use backend=%s model=%s format=%s voice=%s language=%s; re-run without --resume",
+					jobID, manifest.Backend, manifest.Model, manifest.Format, manifest.Voice, manifest.Language, backendName, model, format, voice, lang,
+				),
+			}
+		}
+		if len(manifest.Chunks) != len(chunks) {
+			return nil, &PodcastError{
+				Stage:   "job resume",
+				Message: fmt.Sprintf("manifest has %d chunk(s) but input now splits into %d; re-run without --resume", len(manifest.Chunks), len(chunks)),
+			}
+		}
+		for i, text := range chunks {
+			if manifest.Chunks[i].Hash != chunkTextHash(text) {
+				return nil, &PodcastError{
+					Stage:   "job resume",
+					Message: fmt.Sprintf("chunk %d no longer matches the original job input; re-run without --resume", i+1),
+				}
+			}
+		}
+		return &manifest, nil
+	}
+
+	manifest := &jobManifest{
+		JobID:    jobID,
+		Backend:  backendName,
+		Model:    model,
+		Format:   format,
+		Voice:    voice,
+		Language: lang,
+		Chunks:   make([]jobChunk, len(chunks)),
+	}
+	for i, text := range chunks {
+		manifest.Chunks[i] = jobChunk{
+			Index:  i,
+			Hash:   chunkTextHash(text),
+			File:   filepath.Join(jobDir, fmt.Sprintf("chunk_%d.%s", i+1, format)),
+			Status: jobChunkPending,
+		}
+	}
+	return manifest, saveManifest(jobDir, manifest)
+}
+
+// generateTTSAudioParallel gère la génération audio en parallèle. Chunks
+// the manifest already marks "done" (from a previous --resume'd attempt)
+// are skipped entirely; a chunk's failure marks it "failed" in the
+// manifest but no longer aborts the other in-flight chunks, so one
+// transient error doesn't waste an otherwise-successful multi-hour run —
+// re-run with --resume <jobid> to retry just the failures.
+func generateTTSAudioParallel(backend TTSBackend, chunks []string, voice, lang, jobDir, cacheDir string, noCache bool, manifest *jobManifest) ([]string, error) {
+	ctx := context.Background()
 	audioFiles := make([]string, len(chunks))
-	errors := make(chan error, len(chunks))
+	errorsCh := make(chan error, len(chunks))
 	progress := make(chan int, len(chunks))
 	var wg sync.WaitGroup
+	var manifestMu sync.Mutex
 
 	// Limiter le nombre de goroutines concurrentes
 	semaphore := make(chan struct{}, 5)
@@ -526,6 +2129,18 @@ func generateTTSAudioParallel(chunks []string, voice string, tmpDir string) ([]s
 	}()
 
 	for i, chunk := range chunks {
+		chunkFile := manifest.Chunks[i].File
+		audioFiles[i] = chunkFile
+
+		if manifest.Chunks[i].Status == jobChunkDone {
+			if _, err := os.Stat(chunkFile); err == nil {
+				progress <- 1
+				continue
+			}
+			// Manifest says done but the file is gone; fall through and
+			// regenerate it.
+		}
+
 		wg.Add(1)
 		go func(index int, text string) {
 			defer wg.Done()
@@ -533,31 +2148,222 @@ func generateTTSAudioParallel(chunks []string, voice string, tmpDir string) ([]s
 			semaphore <- struct{}{}
 			defer func() { <-semaphore }()
 
-			chunkFile := filepath.Join(tmpDir, fmt.Sprintf("chunk_%d.mp3", index+1))
-			err := generateTTSAudio(text, voice, chunkFile)
+			err := generateTTSAudio(ctx, backend, text, voice, lang, manifest.Chunks[index].File, cacheDir, noCache)
+
+			manifestMu.Lock()
 			if err != nil {
-				errors <- fmt.Errorf("chunk %d: %w", index+1, err)
+				manifest.Chunks[index].Status = jobChunkFailed
+			} else {
+				manifest.Chunks[index].Status = jobChunkDone
+			}
+			_ = saveManifest(jobDir, manifest) // best-effort: a save failure shouldn't abort the run
+			manifestMu.Unlock()
+
+			if err != nil {
+				errorsCh <- fmt.Errorf("chunk %d: %w", index+1, err)
 				return
 			}
-			audioFiles[index] = chunkFile
 			progress <- 1 // Signaler qu'un chunk est terminé
 		}(i, chunk)
 	}
 
 	// Attendre que toutes les goroutines soient terminées
 	wg.Wait()
-	close(errors)
+	close(errorsCh)
 	close(progress)
 
 	// Attendre que l'affichage de la progression soit terminé
 	<-progressDone
 
-	// Vérifier s'il y a eu des erreurs
-	for err := range errors {
-		if err != nil {
-			return nil, err
-		}
+	// Collecter les erreurs sans interrompre les chunks qui ont réussi : le
+	// job reste résumable via --resume pour ne relancer que les échecs.
+	var failures []error
+	for err := range errorsCh {
+		failures = append(failures, err)
+	}
+	if len(failures) > 0 {
+		return audioFiles, fmt.Errorf("%d chunk(s) failed after retries; fix the cause and re-run with --resume %s (first failure: %w)", len(failures), manifest.JobID, failures[0])
 	}
 
 	return audioFiles, nil
 }
+
+// speechRequest mirrors OpenAI's POST /v1/audio/speech request schema.
+type speechRequest struct {
+	Model          string `json:"model"`
+	Input          string `json:"input"`
+	Voice          string `json:"voice"`
+	ResponseFormat string `json:"response_format"`
+	// Speed is a pointer so an absent field (the common case, meaning "use
+	// the default") is distinguishable from an explicit 1.0 — no backend
+	// here supports anything else, so any other value is rejected rather
+	// than silently ignored.
+	Speed *float64 `json:"speed"`
+}
+
+// streamChunksInOrder synthesizes chunks concurrently, bounded the same way
+// as generateTTSAudioParallel, and writes each chunk's audio to w in
+// original order as soon as it's ready — a reorder buffer so a slow chunk
+// doesn't stall ones that finished after it, while the client still
+// receives bytes in the right sequence. Each chunk's raw Synthesize()
+// output is never forwarded as-is: for MP3 it's run through the same
+// extractMP3Frames stripping concatenateMP3Files uses, and for WAV only the
+// first chunk's header is kept (with an unknown-length size field, since
+// the total isn't known until the stream ends) and later chunks contribute
+// just their PCM payload — otherwise the stray ID3/Xing headers or repeated
+// WAV headers at every chunk boundary would corrupt playback past the
+// first chunk, the same bug the frame-aware merger exists to avoid.
+func streamChunksInOrder(ctx context.Context, backend TTSBackend, chunks []string, voice, lang, cacheDir string, noCache bool, w io.Writer) error {
+	type result struct {
+		audio []byte
+		err   error
+	}
+
+	results := make([]chan result, len(chunks))
+	for i := range results {
+		results[i] = make(chan result, 1)
+	}
+
+	semaphore := make(chan struct{}, 5)
+	for i, chunk := range chunks {
+		go func(index int, text string) {
+			semaphore <- struct{}{}
+			defer func() { <-semaphore }()
+
+			audio, err := withBackendRetry(ctx, backend, text, voice, lang, cacheDir, noCache)
+			results[index] <- result{audio: audio, err: err}
+		}(i, chunk)
+	}
+
+	flusher, _ := w.(http.Flusher)
+
+	var mp3Reference *mp3FrameHeader
+	wavHeaderWritten := false
+
+	for i, ch := range results {
+		r := <-ch
+		if r.err != nil {
+			return fmt.Errorf("chunk %d: %w", i+1, r.err)
+		}
+
+		switch backend.Format() {
+		case "wav":
+			wavFmt, payload, err := parseWAVChunks(r.audio)
+			if err != nil {
+				return fmt.Errorf("chunk %d: invalid WAV: %w", i+1, err)
+			}
+			if !wavHeaderWritten {
+				if _, err := w.Write(buildWAVHeader(wavFmt, -1)); err != nil {
+					return err
+				}
+				wavHeaderWritten = true
+			}
+			if _, err := w.Write(payload); err != nil {
+				return err
+			}
+		default:
+			frames, err := extractMP3Frames(r.audio, &mp3Reference)
+			if err != nil {
+				return fmt.Errorf("chunk %d: invalid MP3: %w", i+1, err)
+			}
+			for _, frame := range frames {
+				if _, err := w.Write(frame); err != nil {
+					return err
+				}
+			}
+		}
+
+		if flusher != nil {
+			flusher.Flush()
+		}
+	}
+
+	return nil
+}
+
+// handleSpeech implements an OpenAI-compatible POST /v1/audio/speech: it
+// streams MP3 bytes back as each chunk finishes synthesizing, via
+// Transfer-Encoding: chunked, so long inputs start playing before the whole
+// job completes.
+func handleSpeech(backend TTSBackend, cacheDir string, noCache bool) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		var req speechRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, fmt.Sprintf("invalid request body: %v", err), http.StatusBadRequest)
+			return
+		}
+		if strings.TrimSpace(req.Input) == "" {
+			http.Error(w, "input is required", http.StatusBadRequest)
+			return
+		}
+		// The response is always in the backend's native container — there's
+		// no re-encoding step — so the only acceptable response_format is
+		// whatever that backend actually produces.
+		if req.ResponseFormat != "" && req.ResponseFormat != backend.Format() {
+			http.Error(w, fmt.Sprintf("only response_format=%s is supported by backend %q", backend.Format(), backend.Name()), http.StatusBadRequest)
+			return
+		}
+		// There's no resampling/time-stretch step, so any speed other than
+		// the implicit 1.0 would silently play back at the wrong rate.
+		if req.Speed != nil && *req.Speed != 1.0 {
+			http.Error(w, "speed is not supported by this backend; omit it or set it to 1.0", http.StatusBadRequest)
+			return
+		}
+		if req.Model != "" && req.Model != backend.Model() {
+			http.Error(w, fmt.Sprintf("model %q is not served by backend %q (expected %q)", req.Model, backend.Name(), backend.Model()), http.StatusBadRequest)
+			return
+		}
+
+		voice := req.Voice
+		if voice == "" {
+			if voices := backend.Voices(); len(voices) > 0 {
+				voice = voices[0]
+			}
+		}
+
+		var chunks []string
+		if isSSMLText(req.Input) {
+			chunks = splitSSMLIntoChunks(req.Input, 2800)
+		} else {
+			chunks = splitTextIntoChunks(req.Input, 2800)
+		}
+
+		contentType := "audio/mpeg"
+		if backend.Format() == "wav" {
+			contentType = "audio/wav"
+		}
+		w.Header().Set("Content-Type", contentType)
+		w.Header().Set("Transfer-Encoding", "chunked")
+		w.WriteHeader(http.StatusOK)
+
+		if err := streamChunksInOrder(r.Context(), backend, chunks, voice, "", cacheDir, noCache, w); err != nil {
+			fmt.Printf("\n❌ Error streaming speech: %v\n", err)
+		}
+	}
+}
+
+// runServe implements the `serve` subcommand: an HTTP server exposing an
+// OpenAI-compatible POST /v1/audio/speech endpoint, so any client built
+// against OpenAI's audio API can point at this tool as a local,
+// streaming-capable TTS proxy.
+func runServe(args []string, backend TTSBackend, cacheDir string, noCache bool) {
+	addr := ":8090"
+	if len(args) > 0 {
+		addr = args[0]
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/v1/audio/speech", handleSpeech(backend, cacheDir, noCache))
+
+	fmt.Println("=== TTS Server ===")
+	fmt.Printf("Listening on %s (POST /v1/audio/speech)\n", addr)
+	if err := http.ListenAndServe(addr, mux); err != nil {
+		fmt.Printf("\n❌ Server error: %v\n", err)
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,35 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSplitIntoSentencesStraightQuotes(t *testing.T) {
+	text := `He said "hello there" to her. She walked away. It was a nice day. The end.`
+	want := []string{
+		`He said "hello there" to her.`,
+		"She walked away.",
+		"It was a nice day.",
+		"The end.",
+	}
+
+	got := splitIntoSentences(text)
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("splitIntoSentences(%q) = %#v, want %#v", text, got, want)
+	}
+}
+
+func TestSplitIntoSentencesMultipleStraightQuotePairs(t *testing.T) {
+	text := `She said "no". He said "yes". Then left.`
+	want := []string{
+		`She said "no".`,
+		`He said "yes".`,
+		"Then left.",
+	}
+
+	got := splitIntoSentences(text)
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("splitIntoSentences(%q) = %#v, want %#v", text, got, want)
+	}
+}